@@ -6,15 +6,27 @@ package main
 import (
 	"embed"
 	"encoding/json"
+	"flag"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/quic-go/webtransport-go"
 	"github.com/sirupsen/logrus"
+
+	"github.com/conradoqg/latency-server/internal/middleware"
+	"github.com/conradoqg/latency-server/internal/probe"
+	"github.com/conradoqg/latency-server/internal/simulate"
+	"github.com/conradoqg/latency-server/internal/stats"
+	"github.com/conradoqg/latency-server/internal/storage"
+	"github.com/conradoqg/latency-server/internal/transport"
+	"github.com/conradoqg/latency-server/internal/wsconn"
 )
 
 // embeddedUI holds the static web UI assets when built into the binary.
@@ -30,40 +42,159 @@ var wsUpgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
+// webtransportSrv upgrades WebTransport-over-HTTP/3 sessions as an
+// alternative to gorilla/websocket when the client negotiates it. It is
+// only set when the server is started with --http3.
+var webtransportSrv *webtransport.Server
+
+// upgradeConn picks the right transport for an incoming /ws/latency
+// request and returns a wsconn.Conn so latencyWSHandler can stay a single
+// implementation regardless of which one it got: a WebTransport session
+// when the client negotiated HTTP/3 and the server has one configured, a
+// plain gorilla WebSocket otherwise.
+func upgradeConn(w http.ResponseWriter, r *http.Request) (wsconn.Conn, error) {
+	if webtransportSrv != nil && r.Method == http.MethodConnect && isWebTransportRequest(r) {
+		session, err := webtransportSrv.Upgrade(w, r)
+		if err != nil {
+			return nil, err
+		}
+		return wsconn.NewWebTransport(session)
+	}
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return wsconn.NewGorilla(conn), nil
+}
+
+// isWebTransportRequest reports whether r is an HTTP/3 extended CONNECT
+// negotiating WebTransport. quic-go/http3 surfaces the :protocol
+// pseudo-header's value via r.Proto rather than as an entry in r.Header,
+// and the current draft sends "webtransport-h3"; "webtransport" is also
+// accepted for the legacy draft some older clients still use.
+func isWebTransportRequest(r *http.Request) bool {
+	return r.Proto == "webtransport-h3" || r.Proto == "webtransport"
+}
+
+// clientTTL is how long a client's samples are retained after its last
+// activity before statsTracker evicts it.
+const clientTTL = 10 * time.Minute
+
+// wsPingInterval is how often latencyWSHandler sends a keep-alive ping to
+// measure packet loss on otherwise idle WebSocket connections.
+const wsPingInterval = 15 * time.Second
+
+// defaultSessionExpiry is how long a saved session stays retrievable when
+// SESSION_EXPIRY is not set.
+const defaultSessionExpiry = 24 * time.Hour
+
+// sessionSweepInterval is how often the mem/file storage backends scan for
+// expired sessions to reclaim.
+const sessionSweepInterval = time.Minute
+
+// statsTracker records per-client RTT, jitter, and packet-loss samples,
+// surfaced via /api/stats and /metrics.
+var statsTracker = stats.NewTracker(clientTTL)
+
 // latencyResp is the JSON response for REST latency endpoint.
 type latencyResp struct {
 	Time int64 `json:"time"`
 }
 
-// latencyRESTHandler handles REST ping requests and returns server time in milliseconds.
+// latencyRESTHandler handles REST ping requests and returns server time in
+// milliseconds. If the client passes its own send time as the "t" query
+// parameter (milliseconds since epoch), the elapsed time is recorded as an
+// RTT sample in statsTracker.
 func latencyRESTHandler(w http.ResponseWriter, r *http.Request) {
 	logrus.Infof("REST /api/latency called from %s", r.RemoteAddr)
+	now := time.Now()
+	if clientMs, err := strconv.ParseInt(r.URL.Query().Get("t"), 10, 64); err == nil {
+		statsTracker.RecordRTT(stats.ClientIDFromRequest(r), stats.RegionFromRequest(r), "rest", now.UnixMilli()-clientMs)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	resp := latencyResp{
-		Time: time.Now().UnixNano() / int64(time.Millisecond),
+		Time: now.UnixNano() / int64(time.Millisecond),
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
-// latencyWSHandler handles WebSocket ping messages by echoing them back.
+// latencyWSHandler handles ping messages by echoing them back over
+// whichever transport upgradeConn picked (WebSocket or WebTransport). It
+// also sends its own keep-alive pings to measure packet loss and tracks
+// RTT samples derived from the client's own timestamps.
 func latencyWSHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	conn, err := upgradeConn(w, r)
 	if err != nil {
 		logrus.Errorf("WebSocket upgrade error: %v", err)
 		return
 	}
 	logrus.Infof("WebSocket /ws/latency connect from %s", r.RemoteAddr)
 	defer conn.Close()
+
+	clientID := stats.ClientIDFromRequest(r)
+	region := stats.RegionFromRequest(r)
+	profile := simulate.FromContext(r)
+
+	// Keep-alive ping/pong-based packet-loss tracking only makes sense on
+	// transports that actually implement it (plain WebSocket); WebTransport
+	// has no ping/pong control frames, so conn.Ping is a no-op there and
+	// would otherwise make every tick look like a missed pong.
+	if conn.SupportsPing() {
+		pingsMissed := 0
+		conn.SetPongHandler(func() {
+			pingsMissed = 0
+		})
+		stopPing := make(chan struct{})
+		defer close(stopPing)
+		go func() {
+			ticker := time.NewTicker(wsPingInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					statsTracker.RecordPingSent(clientID, region)
+					if pingsMissed > 0 {
+						statsTracker.RecordPingMissed(clientID, region)
+					}
+					pingsMissed++
+					if err := conn.Ping(time.Now().Add(5 * time.Second)); err != nil {
+						return
+					}
+				case <-stopPing:
+					return
+				}
+			}
+		}()
+	}
+
 	for {
-		msgType, msg, err := conn.ReadMessage()
+		msg, err := conn.ReadMessage()
 		if err != nil {
 			logrus.Warnf("WebSocket read error: %v", err)
 			break
 		}
 		logrus.Debugf("WebSocket message from %s: %s", r.RemoteAddr, string(msg))
-		if err := conn.WriteMessage(msgType, msg); err != nil {
+		var ping struct {
+			T int64 `json:"t"`
+		}
+		if json.Unmarshal(msg, &ping) == nil && ping.T > 0 {
+			statsTracker.RecordRTT(clientID, region, "ws", time.Now().UnixMilli()-ping.T)
+		}
+
+		if profile != nil {
+			if profile.ShouldDrop() {
+				// Simulate a lost packet: silently drop this message
+				// instead of echoing it back.
+				continue
+			}
+			time.Sleep(profile.Delay())
+			if profile.ByteRatePerSec > 0 {
+				time.Sleep(time.Duration(float64(len(msg)) / float64(profile.ByteRatePerSec) * float64(time.Second)))
+			}
+		}
+		if err := conn.WriteMessage(msg); err != nil {
 			logrus.Warnf("WebSocket write error: %v", err)
 			break
 		}
@@ -71,6 +202,49 @@ func latencyWSHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	profileFlag := flag.String("profile", "", "artificial network condition to simulate (e.g. 3g-lossy, satellite, edge-50ms); falls back to LATENCY_PROFILE")
+	storageTypeFlag := flag.String("storage-type", "mem", "session storage backend: mem, file, or redis")
+	storageAddrFlag := flag.String("storage-addr", "./sessions", "storage-type=file directory, or storage-type=redis address")
+	accessLogFlag := flag.String("access-log", "", "file to write Apache combined-log-format access logs to; falls back to LOG_FILE, then stdout")
+	targetsFlag := flag.String("targets", "", "YAML file declaring upstream targets to probe for /api/targets and /ws/traffic")
+	tlsCertFlag := flag.String("tls-cert", "", "TLS certificate file; enables HTTPS/HTTP2 and is required for --http3")
+	tlsKeyFlag := flag.String("tls-key", "", "TLS private key file; enables HTTPS/HTTP2 and is required for --http3")
+	http3Flag := flag.Bool("http3", false, "also serve over HTTP/3 (QUIC) on the same address; requires --tls-cert/--tls-key")
+	flag.Parse()
+
+	profile, err := simulate.ResolveProfile(*profileFlag)
+	if err != nil {
+		logrus.Fatalf("invalid --profile: %v", err)
+	}
+
+	sessionStore, err := storage.New(*storageTypeFlag, *storageAddrFlag)
+	if err != nil {
+		logrus.Fatalf("invalid --storage-type: %v", err)
+	}
+	sessionExpiry := defaultSessionExpiry
+	if v := os.Getenv("SESSION_EXPIRY"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			logrus.Fatalf("invalid SESSION_EXPIRY %q: %v", v, err)
+		}
+		sessionExpiry = time.Duration(seconds) * time.Second
+	}
+
+	var targets []*probe.Target
+	if *targetsFlag != "" {
+		targets, err = probe.LoadTargets(*targetsFlag)
+		if err != nil {
+			logrus.Fatalf("invalid --targets: %v", err)
+		}
+		if os.Getenv("API_SECRET") == "" {
+			logrus.Warn("--targets is set but API_SECRET is empty: /api/targets and /ws/traffic are unauthenticated, and /api/targets can be used to make the server issue requests to arbitrary URLs (via ?url=) or targets.yaml hosts. Set API_SECRET to require a bearer token.")
+		}
+	}
+	prober := probe.NewProber(targets, nil)
+	if profile != nil {
+		logrus.Infof("Simulating network profile %q (delay %v-%v, jitter %s, drop %.2f%%)", profile.Name, profile.MinDelay, profile.MaxDelay, profile.Jitter, profile.DropProbability*100)
+	}
+
 	// Configure logging from LOG_LEVEL env var (debug, info, warn, error, fatal, panic)
 	levelStr := os.Getenv("LOG_LEVEL")
 	// default to 'warn' if not set
@@ -84,6 +258,21 @@ func main() {
 	logrus.SetLevel(level)
 	logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
 	logrus.Infof("latency-server version %s", Version)
+
+	accessLogPath := *accessLogFlag
+	if accessLogPath == "" {
+		accessLogPath = os.Getenv("LOG_FILE")
+	}
+	accessLogOut := io.Writer(os.Stdout)
+	if accessLogPath != "" {
+		f, err := os.OpenFile(accessLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			logrus.Fatalf("failed to open access log %q: %v", accessLogPath, err)
+		}
+		defer f.Close()
+		accessLogOut = f
+		logrus.Infof("Writing access log to %s", accessLogPath)
+	}
 	// serve static files: from local ./ui if available, else from embedded assets
 	var fileSystem http.FileSystem
 	var useLocal bool
@@ -100,12 +289,32 @@ func main() {
 		logrus.Infof("Serving embedded UI assets")
 	}
 
+	// simulateMW wraps a handler with the resolved network-condition
+	// profile; it is a no-op when no --profile/LATENCY_PROFILE was set.
+	simulateMW := simulate.Middleware(profile)
+
 	// Create router and attach handlers
 	mux := http.NewServeMux()
 	// REST endpoint for latency measurement
-	mux.HandleFunc("/api/latency", latencyRESTHandler)
+	mux.Handle("/api/latency", simulateMW(http.HandlerFunc(latencyRESTHandler)))
 	// WebSocket endpoint for latency measurement
-	mux.HandleFunc("/ws/latency", latencyWSHandler)
+	mux.Handle("/ws/latency", simulateMW(http.HandlerFunc(latencyWSHandler)))
+	// JSON snapshot of per-client latency/jitter/loss statistics
+	mux.HandleFunc("/api/stats", stats.Handler(statsTracker))
+	// Prometheus scrape endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+	go statsTracker.Run(nil)
+	// Persist and retrieve shareable measurement sessions
+	sessionsHandler := storage.SessionsHandler(sessionStore, sessionExpiry)
+	mux.HandleFunc("/api/sessions", sessionsHandler)
+	mux.HandleFunc("/api/sessions/", sessionsHandler)
+	go storage.SweepExpired(sessionStore, sessionSweepInterval, nil)
+	// Multi-target upstream latency probing, Clash-style controller API
+	requireSecret := probe.RequireBearerToken(os.Getenv("API_SECRET"))
+	mux.Handle("/api/targets", requireSecret(probe.TargetsHandler(prober)))
+	mux.Handle("/api/targets/", requireSecret(probe.TargetsHandler(prober)))
+	mux.Handle("/ws/traffic", requireSecret(probe.TrafficHandler(prober)))
+	go prober.Run(nil)
 	// Serve index.html with PAGE_SUFFIX injection and other static assets
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// If root or index.html, inject PAGE_SUFFIX placeholder
@@ -141,9 +350,33 @@ func main() {
 		http.FileServer(fileSystem).ServeHTTP(w, r)
 	})
 
+	// Every route registered above inherits access logging and response
+	// compression by wrapping the mux itself, rather than each handler.
+	handler := middleware.Chain(middleware.AccessLog(accessLogOut), middleware.Compress)(mux)
+
 	addr := ":8080"
-	logrus.Infof("Starting server on %s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	tCfg := transport.Config{
+		Addr:     addr,
+		Handler:  handler,
+		CertFile: *tlsCertFlag,
+		KeyFile:  *tlsKeyFlag,
+		HTTP3:    *http3Flag,
+	}
+	if tCfg.HTTP3 {
+		// webtransportSrv must be the exact instance passed to
+		// transport.ListenAndServe below: Upgrade depends on
+		// per-connection state recorded by its own H3 listener, so a
+		// second, independently constructed server can't upgrade
+		// sessions accepted by the first.
+		webtransportSrv = transport.NewHTTP3Server(tCfg)
+	}
+	srv, err := transport.NewServer(tCfg)
+	if err != nil {
+		logrus.Fatalf("invalid transport configuration: %v", err)
+	}
+
+	logrus.Infof("Starting server on %s (tls=%t http3=%t)", addr, tCfg.CertFile != "", tCfg.HTTP3)
+	if err := transport.ListenAndServe(srv, tCfg, webtransportSrv); err != nil {
 		logrus.Fatal(err)
 	}
 }
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// recorder wraps an http.ResponseWriter to capture the status code and
+// byte count written, for logging after the handler returns.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(p)
+	rec.bytes += n
+	return n, err
+}
+
+// Hijack delegates to the underlying ResponseWriter's http.Hijacker.
+// Embedding http.ResponseWriter as an interface field does not promote
+// Hijacker, so without this override gorilla/websocket's Upgrade (which
+// requires Hijacker for HTTP/1.1) would fail for every request wrapped by
+// recorder, breaking /ws/latency and /ws/traffic entirely.
+func (rec *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("middleware: underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
+
+// AccessLog returns a middleware that writes one Apache combined-log-format
+// line per request to out, alongside whatever the application logs via
+// logrus. WebSocket handshakes are logged like any other request: recorder
+// forwards Hijack to the underlying ResponseWriter so the upgrade still
+// succeeds.
+func AccessLog(out io.Writer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			fmt.Fprintf(out, "%s - - [%s] \"%s %s %s\" %d %d \"%s\" \"%s\"\n",
+				remoteHost(r),
+				start.Format("02/Jan/2006:15:04:05 -0700"),
+				r.Method, r.URL.RequestURI(), r.Proto,
+				rec.status, rec.bytes,
+				r.Referer(), r.UserAgent(),
+			)
+		})
+	}
+}
+
+func remoteHost(r *http.Request) string {
+	host := r.RemoteAddr
+	if i := strings.LastIndex(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	return host
+}
@@ -0,0 +1,42 @@
+// Package middleware provides composable http.Handler wrappers — access
+// logging and response compression — that apply uniformly across every
+// route registered on the mux, so new handlers (stats, sessions, ...)
+// automatically inherit them without each one opting in individually.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Chain composes middlewares into a single one. The first middleware
+// passed is the outermost: it sees the request first and the response
+// last.
+func Chain(mw ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
+// isWebSocketUpgrade reports whether r is a WebSocket handshake request,
+// which must pass through Compress untouched: a hijacked connection can't
+// be wrapped in a compressing ResponseWriter.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return tokenContains(r.Header.Get("Connection"), "upgrade") &&
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// tokenContains reports whether token appears, case-insensitively, among
+// the comma-separated values of header (e.g. the Connection header can
+// list several tokens: "keep-alive, Upgrade").
+func tokenContains(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressWriter wraps an http.ResponseWriter, transparently routing
+// writes through a compressing io.WriteCloser.
+type compressWriter struct {
+	http.ResponseWriter
+	io.WriteCloser
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	return w.WriteCloser.Write(p)
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	// The compressed body length differs from the uncompressed
+	// Content-Length the handler may have set, so drop it and let the
+	// transport fall back to chunked encoding.
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Compress returns a middleware that transparently compresses responses
+// with Brotli or gzip, chosen from the request's Accept-Encoding header
+// (Brotli preferred when the client accepts both). WebSocket handshakes
+// pass through untouched: the connection is hijacked by the upgrader
+// immediately after and can't be wrapped in a compressing writer.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		accept := r.Header.Get("Accept-Encoding")
+		switch {
+		case tokenContains(accept, "br"):
+			w.Header().Set("Content-Encoding", "br")
+			bw := brotli.NewWriter(w)
+			defer bw.Close()
+			next.ServeHTTP(&compressWriter{ResponseWriter: w, WriteCloser: bw}, r)
+		case tokenContains(accept, "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			defer gw.Close()
+			next.ServeHTTP(&compressWriter{ResponseWriter: w, WriteCloser: gw}, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestAccessLogAndCompressPreserveWebSocketUpgrade guards against the
+// Hijacker-promotion bug: wrapping an http.ResponseWriter in a struct that
+// merely embeds the interface (without forwarding Hijack) breaks every WS
+// upgrade silently, since the failure only shows up as a handshake error
+// at runtime rather than a compile error.
+func TestAccessLogAndCompressPreserveWebSocketUpgrade(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed inside handler: %v", err)
+			return
+		}
+		defer conn.Close()
+		mt, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(mt, msg)
+	})
+
+	var logged strings.Builder
+	chained := Chain(AccessLog(&logged), Compress)(echo)
+
+	// AccessLog writes its line only after the handler returns, so the
+	// check at the end waits for that instead of racing it.
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		chained.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/echo"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("client dial failed (upgrade likely broken by the middleware chain): %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(msg) != "ping" {
+		t.Errorf("echoed message = %q, want %q", msg, "ping")
+	}
+
+	<-done
+	if logged.Len() == 0 {
+		t.Error("expected AccessLog to still record the WebSocket handshake request")
+	}
+}
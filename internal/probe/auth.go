@@ -0,0 +1,28 @@
+package probe
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireBearerToken returns a middleware that rejects requests whose
+// Authorization header doesn't present the exact shared secret, matching
+// Clash's external-controller secret scheme. An empty secret disables the
+// check, so the controller only needs auth once an operator sets
+// API_SECRET.
+func RequireBearerToken(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if secret == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package probe
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rawTarget mirrors the on-disk YAML shape; interval/timeout are strings
+// (e.g. "30s") because yaml.v3 has no native time.Duration support.
+type rawTarget struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`
+	Method   string `yaml:"method"`
+	Interval string `yaml:"interval"`
+	Timeout  string `yaml:"timeout"`
+}
+
+// LoadTargets reads a YAML file declaring the upstream targets to probe,
+// in the shape:
+//
+//	- name: api
+//	  url: https://example.com/health
+//	  method: GET
+//	  interval: 30s
+//	  timeout: 5s
+func LoadTargets(path string) ([]*Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("probe: reading targets file %s: %w", path, err)
+	}
+
+	var raw []rawTarget
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("probe: parsing targets file %s: %w", path, err)
+	}
+
+	targets := make([]*Target, 0, len(raw))
+	for _, rt := range raw {
+		if rt.Name == "" || rt.URL == "" {
+			return nil, fmt.Errorf("probe: targets file %s: every target needs a name and url", path)
+		}
+		interval, err := time.ParseDuration(rt.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("probe: target %q has invalid interval: %w", rt.Name, err)
+		}
+		timeout, err := time.ParseDuration(rt.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("probe: target %q has invalid timeout: %w", rt.Name, err)
+		}
+		method := rt.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		targets = append(targets, &Target{
+			Name:     rt.Name,
+			URL:      rt.URL,
+			Method:   method,
+			Interval: interval,
+			Timeout:  timeout,
+		})
+	}
+	return targets, nil
+}
@@ -0,0 +1,57 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TargetsHandler serves GET /api/targets (list configured probes) and
+// GET /api/targets/{name}/delay?timeout=5000&url=... (on-demand
+// measurement), mirroring Clash's external-controller /proxies endpoints.
+func TargetsHandler(prober *Prober) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/targets"), "/")
+
+		switch {
+		case path == "":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(prober.List())
+		case strings.HasSuffix(path, "/delay"):
+			name := strings.TrimSuffix(path, "/delay")
+			measureDelay(prober, strings.TrimSuffix(name, "/"), w, r)
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}
+}
+
+func measureDelay(prober *Prober, name string, w http.ResponseWriter, r *http.Request) {
+	var timeout time.Duration
+	if ms := r.URL.Query().Get("timeout"); ms != "" {
+		v, err := strconv.Atoi(ms)
+		if err != nil {
+			http.Error(w, "invalid timeout", http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(v) * time.Millisecond
+	}
+
+	delay, mean, err := prober.Measure(name, r.URL.Query().Get("url"), timeout)
+	if err == ErrUnknownTarget {
+		http.Error(w, "unknown target", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Delay     int64 `json:"delay"`
+		MeanDelay int64 `json:"meanDelay"`
+	}{delay, mean})
+}
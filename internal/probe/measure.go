@@ -0,0 +1,30 @@
+package probe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// measureOnce issues a single request and returns the observed
+// round-trip time in milliseconds.
+func measureOnce(client *http.Client, method, url string, timeout time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return time.Since(start).Milliseconds(), nil
+}
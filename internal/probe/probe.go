@@ -0,0 +1,24 @@
+// Package probe periodically measures the latency of a configured set of
+// upstream HTTP targets and exposes the results through a REST +
+// WebSocket control API modeled on Clash's external-controller pattern:
+// GET /api/targets lists configured probes, GET /api/targets/{name}/delay
+// triggers an on-demand measurement, and /ws/traffic streams live samples.
+package probe
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnknownTarget is returned by Prober.Measure for a name that was not
+// declared in the targets configuration.
+var ErrUnknownTarget = errors.New("probe: unknown target")
+
+// Target describes one upstream endpoint to probe on a schedule.
+type Target struct {
+	Name     string
+	URL      string
+	Method   string
+	Interval time.Duration
+	Timeout  time.Duration
+}
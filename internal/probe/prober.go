@@ -0,0 +1,203 @@
+package probe
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ringSize is the number of most-recent samples retained per target.
+const ringSize = 64
+
+// sample is one latency measurement for a target.
+type sample struct {
+	Delay int64
+	At    time.Time
+}
+
+// trackedTarget pairs a Target with its recent samples.
+type trackedTarget struct {
+	target *Target
+
+	mu      sync.Mutex
+	samples []sample // oldest first, capped at ringSize
+}
+
+func (t *trackedTarget) push(s sample) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples = append(t.samples, s)
+	if len(t.samples) > ringSize {
+		t.samples = t.samples[len(t.samples)-ringSize:]
+	}
+}
+
+// snapshot returns the latest delay, the mean delay across retained
+// samples, and how many samples are retained.
+func (t *trackedTarget) snapshot() (delay, mean int64, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n = len(t.samples)
+	if n == 0 {
+		return 0, 0, 0
+	}
+	delay = t.samples[n-1].Delay
+	var sum int64
+	for _, s := range t.samples {
+		sum += s.Delay
+	}
+	return delay, sum / int64(n), n
+}
+
+// TargetStatus is the JSON shape returned by GET /api/targets.
+type TargetStatus struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Delay     int64  `json:"delay"`
+	MeanDelay int64  `json:"meanDelay"`
+	Samples   int    `json:"samples"`
+}
+
+// Sample is one live measurement broadcast over /ws/traffic.
+type Sample struct {
+	Target string    `json:"target"`
+	Delay  int64     `json:"delay"`
+	At     time.Time `json:"at"`
+}
+
+// Prober periodically measures a configured set of upstream targets and
+// keeps a ring of recent samples for each. The zero value is not usable;
+// create one with NewProber.
+type Prober struct {
+	client  *http.Client
+	targets map[string]*trackedTarget
+	order   []string // preserves config order for List
+
+	subMu sync.Mutex
+	subs  map[chan Sample]struct{}
+}
+
+// NewProber creates a Prober for targets. If client is nil, a default
+// *http.Client is used for all outbound probes.
+func NewProber(targets []*Target, client *http.Client) *Prober {
+	if client == nil {
+		client = &http.Client{}
+	}
+	p := &Prober{
+		client:  client,
+		targets: make(map[string]*trackedTarget, len(targets)),
+		subs:    make(map[chan Sample]struct{}),
+	}
+	for _, t := range targets {
+		p.targets[t.Name] = &trackedTarget{target: t}
+		p.order = append(p.order, t.Name)
+	}
+	return p
+}
+
+// Run starts one scheduling goroutine per target, probing it on its
+// configured interval until stop is closed.
+func (p *Prober) Run(stop <-chan struct{}) {
+	for _, name := range p.order {
+		go p.schedule(p.targets[name], stop)
+	}
+}
+
+func (p *Prober) schedule(tt *trackedTarget, stop <-chan struct{}) {
+	ticker := time.NewTicker(tt.target.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.probeOnce(tt, tt.target.Method, tt.target.URL, tt.target.Timeout)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *Prober) probeOnce(tt *trackedTarget, method, url string, timeout time.Duration) (int64, error) {
+	delay, err := measureOnce(p.client, method, url, timeout)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	tt.push(sample{Delay: delay, At: now})
+	p.broadcast(Sample{Target: tt.target.Name, Delay: delay, At: now})
+	return delay, nil
+}
+
+// Measure triggers an on-demand measurement of the named target,
+// optionally overriding its configured URL (urlOverride != "") and
+// timeout (timeoutOverride > 0), and returns the resulting delay together
+// with the target's rolling mean delay.
+func (p *Prober) Measure(name, urlOverride string, timeoutOverride time.Duration) (delay, mean int64, err error) {
+	tt, ok := p.targets[name]
+	if !ok {
+		return 0, 0, ErrUnknownTarget
+	}
+
+	url := tt.target.URL
+	if urlOverride != "" {
+		url = urlOverride
+	}
+	timeout := tt.target.Timeout
+	if timeoutOverride > 0 {
+		timeout = timeoutOverride
+	}
+
+	delay, err = p.probeOnce(tt, tt.target.Method, url, timeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, mean, _ = tt.snapshot()
+	return delay, mean, nil
+}
+
+// List returns the current status of every configured target, in config
+// order.
+func (p *Prober) List() []TargetStatus {
+	out := make([]TargetStatus, 0, len(p.order))
+	for _, name := range p.order {
+		tt := p.targets[name]
+		delay, mean, n := tt.snapshot()
+		out = append(out, TargetStatus{
+			Name:      tt.target.Name,
+			URL:       tt.target.URL,
+			Delay:     delay,
+			MeanDelay: mean,
+			Samples:   n,
+		})
+	}
+	return out
+}
+
+// Subscribe registers a channel that receives every future Sample.
+// Callers must call Unsubscribe when done to avoid leaking it.
+func (p *Prober) Subscribe() chan Sample {
+	ch := make(chan Sample, 16)
+	p.subMu.Lock()
+	p.subs[ch] = struct{}{}
+	p.subMu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes and closes a channel registered with Subscribe.
+func (p *Prober) Unsubscribe(ch chan Sample) {
+	p.subMu.Lock()
+	delete(p.subs, ch)
+	p.subMu.Unlock()
+	close(ch)
+}
+
+func (p *Prober) broadcast(s Sample) {
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+	for ch := range p.subs {
+		select {
+		case ch <- s:
+		default:
+			// Slow subscriber: drop the sample rather than block probing.
+		}
+	}
+}
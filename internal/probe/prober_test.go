@@ -0,0 +1,36 @@
+package probe
+
+import "testing"
+
+func TestTrackedTargetSnapshotMean(t *testing.T) {
+	tt := &trackedTarget{target: &Target{Name: "api"}}
+	for _, d := range []int64{10, 20, 30} {
+		tt.push(sample{Delay: d})
+	}
+
+	delay, mean, n := tt.snapshot()
+	if n != 3 {
+		t.Fatalf("n = %d, want 3", n)
+	}
+	if delay != 30 {
+		t.Errorf("delay = %d, want 30 (most recent)", delay)
+	}
+	if mean != 20 {
+		t.Errorf("mean = %d, want 20", mean)
+	}
+}
+
+func TestTrackedTargetSnapshotEmpty(t *testing.T) {
+	tt := &trackedTarget{target: &Target{Name: "api"}}
+	delay, mean, n := tt.snapshot()
+	if delay != 0 || mean != 0 || n != 0 {
+		t.Errorf("snapshot of empty target = (%d, %d, %d), want zeros", delay, mean, n)
+	}
+}
+
+func TestProberMeasureUnknownTarget(t *testing.T) {
+	p := NewProber(nil, nil)
+	if _, _, err := p.Measure("missing", "", 0); err != ErrUnknownTarget {
+		t.Errorf("Measure(missing) error = %v, want ErrUnknownTarget", err)
+	}
+}
@@ -0,0 +1,33 @@
+package probe
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// trafficUpgrader upgrades /ws/traffic connections.
+var trafficUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// TrafficHandler upgrades to a WebSocket and streams every Sample recorded
+// by prober as a JSON frame, mirroring Clash's /traffic stream.
+func TrafficHandler(prober *Prober) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := trafficUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ch := prober.Subscribe()
+		defer prober.Unsubscribe(ch)
+
+		for s := range ch {
+			if err := conn.WriteJSON(s); err != nil {
+				return
+			}
+		}
+	}
+}
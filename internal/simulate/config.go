@@ -0,0 +1,21 @@
+package simulate
+
+import "os"
+
+// ProfileEnvVar is the environment variable consulted for the active
+// profile name when --profile is not set.
+const ProfileEnvVar = "LATENCY_PROFILE"
+
+// ResolveProfile loads the profile selected by flagValue, falling back to
+// the LATENCY_PROFILE environment variable, and returns (nil, nil) if
+// neither is set so callers can run with simulation disabled.
+func ResolveProfile(flagValue string) (*Profile, error) {
+	name := flagValue
+	if name == "" {
+		name = os.Getenv(ProfileEnvVar)
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return LoadProfile(name)
+}
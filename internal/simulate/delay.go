@@ -0,0 +1,52 @@
+package simulate
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Delay samples one artificial-delay duration for this profile according
+// to its configured jitter distribution.
+func (p *Profile) Delay() time.Duration {
+	if p.MaxDelay <= p.MinDelay {
+		return p.MinDelay
+	}
+	span := float64(p.MaxDelay - p.MinDelay)
+
+	switch p.Jitter {
+	case DistributionNormal:
+		// Sample around the midpoint with the window covering roughly 3
+		// standard deviations, then clamp back into [0, span].
+		mid := span / 2
+		v := rand.NormFloat64()*(mid/3) + mid
+		return p.MinDelay + time.Duration(clamp(v, 0, span))
+	case DistributionPareto:
+		// Skewed toward MinDelay with a tail reaching toward MaxDelay: a
+		// Pareto(alpha=2) sample x in [1, inf) is normalized into the
+		// window as 1 - 1/x.
+		const alpha = 2.0
+		u := rand.Float64()
+		x := math.Pow(1-u, -1/alpha)
+		v := span * (1 - 1/x)
+		return p.MinDelay + time.Duration(clamp(v, 0, span))
+	default: // DistributionUniform
+		return p.MinDelay + time.Duration(rand.Float64()*span)
+	}
+}
+
+// ShouldDrop reports whether this call should be dropped to simulate
+// packet loss, based on DropProbability.
+func (p *Profile) ShouldDrop() bool {
+	return p.DropProbability > 0 && rand.Float64() < p.DropProbability
+}
+
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
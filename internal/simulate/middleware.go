@@ -0,0 +1,84 @@
+package simulate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// profileContextKey is the context key under which the active Profile is
+// stored by Middleware, so handlers that need per-message simulation (the
+// WebSocket handler) can look it up with FromContext.
+type profileContextKey struct{}
+
+// FromContext returns the Profile applied to r by Middleware, or nil if no
+// simulation is active.
+func FromContext(r *http.Request) *Profile {
+	p, _ := r.Context().Value(profileContextKey{}).(*Profile)
+	return p
+}
+
+// Middleware wraps next with artificial latency, jitter, packet loss, and
+// byte-rate throttling drawn from profile. A nil profile is a no-op, so
+// callers can always wrap their handlers and simply not select a profile
+// by default. It is safe to use in front of both REST and WebSocket
+// handlers: the delay and drop decision happen before the request reaches
+// next, and the chosen Profile is attached to the request context for
+// handlers (like the WebSocket upgrade path) that need to keep simulating
+// conditions message-by-message after the initial handshake.
+func Middleware(profile *Profile) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if profile == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if profile.ShouldDrop() {
+				// Simulate a lost packet by closing the connection instead
+				// of writing any response.
+				if hj, ok := w.(http.Hijacker); ok {
+					if conn, _, err := hj.Hijack(); err == nil {
+						conn.Close()
+						return
+					}
+				}
+				http.Error(w, "simulated packet loss", http.StatusServiceUnavailable)
+				return
+			}
+			time.Sleep(profile.Delay())
+
+			r = r.WithContext(context.WithValue(r.Context(), profileContextKey{}, profile))
+			if profile.ByteRatePerSec > 0 {
+				w = &throttledWriter{ResponseWriter: w, bytesPerSec: profile.ByteRatePerSec}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// throttledWriter caps outgoing REST throughput to bytesPerSec by sleeping
+// proportionally to the size of each write.
+type throttledWriter struct {
+	http.ResponseWriter
+	bytesPerSec int64
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Duration(float64(len(p)) / float64(t.bytesPerSec) * float64(time.Second)))
+	return t.ResponseWriter.Write(p)
+}
+
+// Hijack delegates to the underlying ResponseWriter's http.Hijacker.
+// Embedding http.ResponseWriter as an interface field does not promote
+// Hijacker, so without this override a throttled profile (e.g. 3g-lossy,
+// satellite) would break every WebSocket upgrade passing through
+// Middleware, since /ws/latency is wrapped by it just like REST handlers.
+func (t *throttledWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := t.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("simulate: underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
@@ -0,0 +1,48 @@
+package simulate
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// hijackableRecorder is an httptest.ResponseRecorder that also implements
+// http.Hijacker, so tests can exercise the Hijack passthrough without a
+// real network connection.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	server, _ := net.Pipe()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+func TestMiddlewareThrottledWriterForwardsHijack(t *testing.T) {
+	profile := &Profile{ByteRatePerSec: 1 << 30}
+	var hijackErr error
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			hijackErr = fmt.Errorf("handler's ResponseWriter does not implement http.Hijacker")
+			return
+		}
+		_, _, hijackErr = hj.Hijack()
+	})
+
+	rec := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/ws/latency", nil)
+	Middleware(profile)(next).ServeHTTP(rec, req)
+
+	if hijackErr != nil {
+		t.Fatalf("Hijack through throttledWriter failed: %v", hijackErr)
+	}
+	if !rec.hijacked {
+		t.Fatal("expected the underlying ResponseWriter's Hijack to be called")
+	}
+}
@@ -0,0 +1,89 @@
+// Package simulate injects configurable artificial latency, jitter,
+// packet loss, and bandwidth limits into HTTP and WebSocket handlers so
+// operators can reproduce real-world network conditions (e.g. a lossy 3G
+// link or a satellite hop) on demand, selected by name via the --profile
+// flag or the LATENCY_PROFILE environment variable.
+package simulate
+
+import (
+	"embed"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed profiles/*.yaml
+var builtinProfiles embed.FS
+
+// Distribution names the shape used to spread delay across a profile's
+// [MinDelay, MaxDelay] window.
+type Distribution string
+
+// Supported jitter distributions.
+const (
+	DistributionUniform Distribution = "uniform"
+	DistributionNormal   Distribution = "normal"
+	DistributionPareto   Distribution = "pareto"
+)
+
+// Profile describes one named artificial network condition.
+type Profile struct {
+	Name            string
+	MinDelay        time.Duration
+	MaxDelay        time.Duration
+	Jitter          Distribution
+	DropProbability float64
+	ByteRatePerSec  int64
+}
+
+// rawProfile mirrors the on-disk YAML shape; durations are strings
+// (e.g. "50ms") because yaml.v3 has no native time.Duration support.
+type rawProfile struct {
+	MinDelay        string  `yaml:"minDelay"`
+	MaxDelay        string  `yaml:"maxDelay"`
+	Jitter          string  `yaml:"jitter"`
+	DropProbability float64 `yaml:"dropProbability"`
+	ByteRatePerSec  int64   `yaml:"byteRatePerSec"`
+}
+
+// LoadProfile loads a built-in profile by name (its YAML filename without
+// extension, e.g. "3g-lossy").
+func LoadProfile(name string) (*Profile, error) {
+	data, err := builtinProfiles.ReadFile("profiles/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("simulate: unknown profile %q: %w", name, err)
+	}
+	return parseProfile(name, data)
+}
+
+func parseProfile(name string, data []byte) (*Profile, error) {
+	var raw rawProfile
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("simulate: parsing profile %q: %w", name, err)
+	}
+	minDelay, err := time.ParseDuration(raw.MinDelay)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: profile %q has invalid minDelay: %w", name, err)
+	}
+	maxDelay, err := time.ParseDuration(raw.MaxDelay)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: profile %q has invalid maxDelay: %w", name, err)
+	}
+	jitter := Distribution(raw.Jitter)
+	switch jitter {
+	case DistributionUniform, DistributionNormal, DistributionPareto:
+	case "":
+		jitter = DistributionUniform
+	default:
+		return nil, fmt.Errorf("simulate: profile %q has unknown jitter distribution %q", name, raw.Jitter)
+	}
+	return &Profile{
+		Name:            name,
+		MinDelay:        minDelay,
+		MaxDelay:        maxDelay,
+		Jitter:          jitter,
+		DropProbability: raw.DropProbability,
+		ByteRatePerSec:  raw.ByteRatePerSec,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package simulate
+
+import "testing"
+
+func TestLoadProfileBuiltins(t *testing.T) {
+	for _, name := range []string{"3g-lossy", "satellite", "edge-50ms"} {
+		p, err := LoadProfile(name)
+		if err != nil {
+			t.Fatalf("LoadProfile(%q) error: %v", name, err)
+		}
+		if p.MaxDelay < p.MinDelay {
+			t.Errorf("profile %q: maxDelay < minDelay", name)
+		}
+		for i := 0; i < 50; i++ {
+			d := p.Delay()
+			if d < p.MinDelay || d > p.MaxDelay {
+				t.Fatalf("profile %q: Delay() = %v, want within [%v, %v]", name, d, p.MinDelay, p.MaxDelay)
+			}
+		}
+	}
+}
+
+func TestLoadProfileUnknown(t *testing.T) {
+	if _, err := LoadProfile("does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestParseProfileRejectsBadJitter(t *testing.T) {
+	_, err := parseProfile("bad", []byte("minDelay: 1ms\nmaxDelay: 2ms\njitter: quantum\n"))
+	if err == nil {
+		t.Fatal("expected error for unknown jitter distribution")
+	}
+}
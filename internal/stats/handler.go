@@ -0,0 +1,17 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler returns an http.HandlerFunc serving the current tracker snapshot
+// as JSON, for mounting at /api/stats.
+func Handler(tracker *Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(tracker.Snapshot()); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	}
+}
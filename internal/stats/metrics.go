@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// regionBuckets bounds the cardinality of the Prometheus "region" label.
+// RegionFromRequest derives its value from the client-controlled
+// X-Forwarded-For header (or the raw peer IP), so using it directly as a
+// label would let a client balloon Prometheus's label cardinality by
+// sending a different value per request.
+const regionBuckets = 32
+
+// metricRegion maps an arbitrary region string (as returned by
+// RegionFromRequest) onto one of a fixed number of buckets, bounding
+// Prometheus label cardinality regardless of how many distinct raw region
+// values are seen. It is only used for metric labels; the per-client map
+// and the /api/stats JSON snapshot keep the raw, human-readable region.
+func metricRegion(region string) string {
+	h := fnv.New32a()
+	h.Write([]byte(region))
+	return fmt.Sprintf("region-%d", h.Sum32()%regionBuckets)
+}
+
+var (
+	rttHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "latency_server_rtt_milliseconds",
+		Help:    "Round-trip latency samples reported by clients, in milliseconds.",
+		Buckets: []float64{5, 10, 25, 50, 100, 200, 400, 800, 1600, 3200},
+	}, []string{"region", "transport"})
+
+	pingsMissedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "latency_server_ws_pings_missed_total",
+		Help: "WebSocket keep-alive pings that did not receive a pong before their deadline.",
+	}, []string{"region"})
+
+	activeClientsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "latency_server_active_clients",
+		Help: "Number of clients with at least one sample inside the TTL eviction window.",
+	})
+)
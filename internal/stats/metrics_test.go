@@ -0,0 +1,22 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMetricRegionIsBounded(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		seen[metricRegion(fmt.Sprintf("203.0.113.%d", i))] = true
+	}
+	if len(seen) > regionBuckets {
+		t.Fatalf("metricRegion produced %d distinct labels from 1000 inputs, want at most %d", len(seen), regionBuckets)
+	}
+}
+
+func TestMetricRegionStable(t *testing.T) {
+	if metricRegion("us-east") != metricRegion("us-east") {
+		t.Error("metricRegion is not deterministic for the same input")
+	}
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// quantile returns the p-th percentile (0-100) of samples as the sorted
+// sample at rank = ceil(p/100 * (n+1)), clamped to [1, n].
+// samples is not mutated.
+func quantile(samples []int64, p float64) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	rank := int(math.Ceil(p / 100 * float64(n+1)))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > n {
+		rank = n
+	}
+	return sorted[rank-1]
+}
+
+// jitter computes the mean absolute deviation between successive samples,
+// i.e. the average magnitude of sample-to-sample change.
+func jitter(samples []int64) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	var sum float64
+	for i := 1; i < len(samples); i++ {
+		d := samples[i] - samples[i-1]
+		if d < 0 {
+			d = -d
+		}
+		sum += float64(d)
+	}
+	return sum / float64(len(samples)-1)
+}
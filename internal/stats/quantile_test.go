@@ -0,0 +1,51 @@
+package stats
+
+import "testing"
+
+func TestQuantile(t *testing.T) {
+	samples := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+	cases := []struct {
+		p    float64
+		want int64
+	}{
+		{0, 10},
+		{50, 60},
+		{90, 100},
+		{100, 100},
+	}
+	for _, c := range cases {
+		if got := quantile(samples, c.p); got != c.want {
+			t.Errorf("quantile(samples, %v) = %d, want %d", c.p, got, c.want)
+		}
+	}
+
+	if got := quantile(nil, 50); got != 0 {
+		t.Errorf("quantile(nil, 50) = %d, want 0", got)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	if got := jitter([]int64{100}); got != 0 {
+		t.Errorf("jitter single sample = %v, want 0", got)
+	}
+	if got := jitter(nil); got != 0 {
+		t.Errorf("jitter nil = %v, want 0", got)
+	}
+
+	// deviations: |20-10|=10, |15-20|=5 -> mean 7.5
+	if got := jitter([]int64{10, 20, 15}); got != 7.5 {
+		t.Errorf("jitter = %v, want 7.5", got)
+	}
+}
+
+func TestRingSnapshotWraps(t *testing.T) {
+	var r ring
+	for i := int64(0); i < ringSize+10; i++ {
+		r.push(i)
+	}
+	snap := r.snapshot()
+	if len(snap) != ringSize {
+		t.Fatalf("snapshot length = %d, want %d", len(snap), ringSize)
+	}
+}
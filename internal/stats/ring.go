@@ -0,0 +1,39 @@
+package stats
+
+import "sync/atomic"
+
+// ringSize is the number of most-recent samples retained per client.
+const ringSize = 256
+
+// ring is a fixed-capacity circular buffer of latency samples (in
+// milliseconds). Writers claim a slot with an atomic increment so
+// concurrent pushes from the same client never block each other; readers
+// take a point-in-time snapshot that may observe a slot mid-write under
+// heavy concurrent use, which is acceptable for quantile estimates.
+type ring struct {
+	samples [ringSize]int64
+	next    uint64
+	count   uint64
+}
+
+// push records a new sample.
+func (r *ring) push(ms int64) {
+	idx := atomic.AddUint64(&r.next, 1) - 1
+	atomic.StoreInt64(&r.samples[idx%ringSize], ms)
+	atomic.AddUint64(&r.count, 1)
+}
+
+// snapshot returns a copy of the samples currently held in the buffer, most
+// recent ringSize at most, oldest first.
+func (r *ring) snapshot() []int64 {
+	n := atomic.LoadUint64(&r.count)
+	if n > ringSize {
+		n = ringSize
+	}
+	start := atomic.LoadUint64(&r.next) - n
+	out := make([]int64, n)
+	for i := uint64(0); i < n; i++ {
+		out[i] = atomic.LoadInt64(&r.samples[(start+i)%ringSize])
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+// Package stats tracks per-client round-trip-time samples for the
+// /api/latency and /ws/latency endpoints, maintaining rolling windows used
+// to compute quantiles, jitter, and packet loss, with idle clients evicted
+// after a TTL. Aggregates are exported both as a JSON snapshot
+// (/api/stats) and as Prometheus metrics (/metrics).
+package stats
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Snapshot is the point-in-time statistics for a single client.
+type Snapshot struct {
+	ClientID    string    `json:"clientId"`
+	Region      string    `json:"region"`
+	Samples     int       `json:"samples"`
+	P50Ms       int64     `json:"p50Ms"`
+	P90Ms       int64     `json:"p90Ms"`
+	P99Ms       int64     `json:"p99Ms"`
+	JitterMs    float64   `json:"jitterMs"`
+	PingsSent   uint64    `json:"pingsSent"`
+	PingsMissed uint64    `json:"pingsMissed"`
+	LastSeen    time.Time `json:"lastSeen"`
+}
+
+// clientStats holds the mutable state tracked for a single client.
+type clientStats struct {
+	region      string
+	rtt         ring
+	pingsSent   uint64
+	pingsMissed uint64
+	lastSeenNs  int64 // unix nanoseconds, accessed atomically
+}
+
+func (c *clientStats) touch() {
+	atomic.StoreInt64(&c.lastSeenNs, time.Now().UnixNano())
+}
+
+func (c *clientStats) idleFor(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&c.lastSeenNs)))
+}
+
+// Tracker aggregates per-client RTT samples and evicts clients that have
+// been idle for longer than its TTL. The zero value is not usable; create
+// one with NewTracker.
+type Tracker struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	clients map[string]*clientStats
+}
+
+// NewTracker creates a Tracker that evicts clients idle for longer than
+// ttl. Call Run in its own goroutine to start the eviction sweeper.
+func NewTracker(ttl time.Duration) *Tracker {
+	return &Tracker{
+		ttl:     ttl,
+		clients: make(map[string]*clientStats),
+	}
+}
+
+// Run sweeps idle clients every ttl/2 until stop is closed. It is intended
+// to be started with `go tracker.Run(stop)`.
+func (t *Tracker) Run(stop <-chan struct{}) {
+	interval := t.ttl / 2
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.evictIdle()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (t *Tracker) evictIdle() {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, c := range t.clients {
+		if c.idleFor(now) > t.ttl {
+			delete(t.clients, id)
+		}
+	}
+	activeClientsGauge.Set(float64(len(t.clients)))
+}
+
+func (t *Tracker) client(clientID, region string) *clientStats {
+	t.mu.RLock()
+	c, ok := t.clients[clientID]
+	t.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c, ok = t.clients[clientID]; ok {
+		return c
+	}
+	c = &clientStats{region: region}
+	t.clients[clientID] = c
+	activeClientsGauge.Set(float64(len(t.clients)))
+	return c
+}
+
+// RecordRTT stores a round-trip-time sample (in milliseconds) for
+// clientID, reported over the given transport ("rest" or "ws").
+func (t *Tracker) RecordRTT(clientID, region, transport string, rttMs int64) {
+	c := t.client(clientID, region)
+	c.rtt.push(rttMs)
+	c.touch()
+	rttHistogram.WithLabelValues(metricRegion(region), transport).Observe(float64(rttMs))
+}
+
+// RecordPingSent marks that a keep-alive ping was sent to clientID over a
+// WebSocket connection.
+func (t *Tracker) RecordPingSent(clientID, region string) {
+	c := t.client(clientID, region)
+	atomic.AddUint64(&c.pingsSent, 1)
+}
+
+// RecordPingMissed marks that a previously sent keep-alive ping did not
+// receive a pong before its deadline, counting as a lost packet.
+func (t *Tracker) RecordPingMissed(clientID, region string) {
+	c := t.client(clientID, region)
+	atomic.AddUint64(&c.pingsMissed, 1)
+	pingsMissedTotal.WithLabelValues(metricRegion(region)).Inc()
+}
+
+// Snapshot returns the current statistics for every tracked client, sorted
+// by client ID for stable output.
+func (t *Tracker) Snapshot() []Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]Snapshot, 0, len(t.clients))
+	for id, c := range t.clients {
+		samples := c.rtt.snapshot()
+		out = append(out, Snapshot{
+			ClientID:    id,
+			Region:      c.region,
+			Samples:     len(samples),
+			P50Ms:       quantile(samples, 50),
+			P90Ms:       quantile(samples, 90),
+			P99Ms:       quantile(samples, 99),
+			JitterMs:    jitter(samples),
+			PingsSent:   atomic.LoadUint64(&c.pingsSent),
+			PingsMissed: atomic.LoadUint64(&c.pingsMissed),
+			LastSeen:    time.Unix(0, atomic.LoadInt64(&c.lastSeenNs)),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClientID < out[j].ClientID })
+	return out
+}
+
+// ClientIDFromRequest derives a stable client identifier from an incoming
+// request: the X-Client-Id header if the client set one, otherwise the
+// connecting IP.
+func ClientIDFromRequest(r *http.Request) string {
+	if id := r.Header.Get("X-Client-Id"); id != "" {
+		return id
+	}
+	return remoteIP(r)
+}
+
+// RegionFromRequest derives a coarse region label for a request. It reads
+// the left-most address in X-Forwarded-For (set by the proxy/load balancer
+// in front of the server) falling back to the direct peer address. This is
+// a placeholder bucketing key; swap in a real GeoIP database lookup by
+// wrapping RegionFromRequest at the call site if per-country granularity is
+// needed.
+func RegionFromRequest(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		parts := strings.Split(fwd, ",")
+		return strings.TrimSpace(parts[0])
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host := r.RemoteAddr
+	if i := strings.LastIndex(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	return host
+}
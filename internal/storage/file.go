@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sessionIDPattern matches the hex shape produced by newSessionID(): 32
+// lowercase hex characters (16 random bytes). id comes straight from the
+// URL path in SessionsHandler, so rejecting anything else before building
+// a filesystem path prevents traversal via a crafted id such as
+// "../../etc/passwd".
+var sessionIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// fileStorage is a Storage backend that keeps one JSON file per session
+// under Dir, surviving process restarts without an external dependency.
+// Selected via --storage-type=file.
+type fileStorage struct {
+	dir string
+}
+
+// NewFile creates a file-backed Storage rooted at dir, creating dir if it
+// does not already exist.
+func NewFile(dir string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: creating %s: %w", dir, err)
+	}
+	return &fileStorage{dir: dir}, nil
+}
+
+func (f *fileStorage) path(id string) (string, error) {
+	if !sessionIDPattern.MatchString(id) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(f.dir, id+".json"), nil
+}
+
+func (f *fileStorage) Save(_ context.Context, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("storage: marshaling session %s: %w", s.ID, err)
+	}
+	path, err := f.path(s.ID)
+	if err != nil {
+		return fmt.Errorf("storage: invalid session id %q: %w", s.ID, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("storage: writing session %s: %w", s.ID, err)
+	}
+	return nil
+}
+
+func (f *fileStorage) Get(_ context.Context, id string) (*Session, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: reading session %s: %w", id, err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("storage: decoding session %s: %w", id, err)
+	}
+	if s.Expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+	return &s, nil
+}
+
+// List returns every session, including expired ones not yet reclaimed by
+// SweepExpired, so the sweeper can find them.
+func (f *fileStorage) List(_ context.Context) ([]*Session, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("storage: listing %s: %w", f.dir, err)
+	}
+	out := make([]*Session, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(f.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			continue
+		}
+		out = append(out, &s)
+	}
+	return out, nil
+}
+
+func (f *fileStorage) Delete(_ context.Context, id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("storage: deleting session %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStorageSaveGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+
+	s := &Session{ID: "0123456789abcdef0123456789abcdef", CreatedAt: time.Now(), Samples: []int64{10, 20, 30}}
+	if err := store.Save(ctx, s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get(ctx, s.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.Samples) != 3 {
+		t.Errorf("Samples = %v, want 3 entries", got.Samples)
+	}
+
+	if err := store.Delete(ctx, s.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, s.ID); err != ErrNotFound {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStorageRejectsPathTraversal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFile(dir)
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+
+	// Plant a file outside the storage dir that a traversing id would reach.
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "leaked.json")
+	if err := os.WriteFile(secret, []byte(`{"id":"leaked"}`), 0o644); err != nil {
+		t.Fatalf("writing secret fixture: %v", err)
+	}
+	traversal := "../" + filepath.Base(outside) + "/leaked"
+
+	if _, err := store.Get(ctx, traversal); err != ErrNotFound {
+		t.Errorf("Get(%q) = %v, want ErrNotFound", traversal, err)
+	}
+	if err := store.Delete(ctx, traversal); err != nil {
+		t.Errorf("Delete(%q) = %v, want nil (no-op for invalid id)", traversal, err)
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Errorf("secret fixture should be untouched, stat err: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// createRequest is the body accepted by POST /api/sessions.
+type createRequest struct {
+	Samples []int64 `json:"samples"`
+}
+
+// createResponse is returned by POST /api/sessions.
+type createResponse struct {
+	ID string `json:"id"`
+}
+
+// SessionsHandler serves both POST /api/sessions (persist a series of
+// samples and return an opaque ID) and GET /api/sessions/{id} (retrieve a
+// previously saved session), so it can be mounted once at the
+// "/api/sessions/" prefix. expiry is how long a saved session remains
+// retrievable.
+func SessionsHandler(store Storage, expiry time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/sessions"), "/")
+
+		switch {
+		case r.Method == http.MethodPost && id == "":
+			createSession(store, expiry, w, r)
+		case r.Method == http.MethodGet && id != "":
+			getSession(store, id, w, r)
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}
+}
+
+func createSession(store Storage, expiry time.Duration, w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:        id,
+		CreatedAt: now,
+		ExpiresAt: now.Add(expiry),
+		Samples:   req.Samples,
+	}
+	if err := store.Save(r.Context(), session); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(createResponse{ID: id})
+}
+
+func getSession(store Storage, id string, w http.ResponseWriter, r *http.Request) {
+	session, err := store.Get(r.Context(), id)
+	if err == ErrNotFound {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memStorage is an in-memory Storage backend. Sessions do not survive a
+// process restart. Selected via --storage-type=mem, the default.
+type memStorage struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewMem creates an in-memory Storage backend.
+func NewMem() Storage {
+	return &memStorage{sessions: make(map[string]*Session)}
+}
+
+func (m *memStorage) Save(_ context.Context, s *Session) error {
+	cp := *s
+	m.mu.Lock()
+	m.sessions[s.ID] = &cp
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memStorage) Get(_ context.Context, id string) (*Session, error) {
+	m.mu.RLock()
+	s, ok := m.sessions[id]
+	m.mu.RUnlock()
+	if !ok || s.Expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+	cp := *s
+	return &cp, nil
+}
+
+// List returns every session, including expired ones not yet reclaimed by
+// SweepExpired, so the sweeper can find them.
+func (m *memStorage) List(_ context.Context) ([]*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		cp := *s
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (m *memStorage) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	return nil
+}
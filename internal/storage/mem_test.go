@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemStorageSaveGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewMem()
+
+	s := &Session{ID: "abc", CreatedAt: time.Now(), Samples: []int64{10, 20, 30}}
+	if err := store.Save(ctx, s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.Samples) != 3 {
+		t.Errorf("Samples = %v, want 3 entries", got.Samples)
+	}
+
+	if err := store.Delete(ctx, "abc"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "abc"); err != ErrNotFound {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemStorageExpiredNotReturnedByGet(t *testing.T) {
+	ctx := context.Background()
+	store := NewMem()
+
+	s := &Session{ID: "expired", CreatedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := store.Save(ctx, s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := store.Get(ctx, "expired"); err != ErrNotFound {
+		t.Errorf("Get expired session = %v, want ErrNotFound", err)
+	}
+
+	sessions, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("List len = %d, want 1 (expired sessions remain until swept)", len(sessions))
+	}
+}
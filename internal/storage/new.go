@@ -0,0 +1,20 @@
+package storage
+
+import "fmt"
+
+// New builds a Storage backend for storageType ("mem", "file", or
+// "redis"), matching the value of the --storage-type flag. addr is the
+// file directory for "file" or the Redis address for "redis"; it is
+// ignored for "mem".
+func New(storageType, addr string) (Storage, error) {
+	switch storageType {
+	case "", "mem":
+		return NewMem(), nil
+	case "file":
+		return NewFile(addr)
+	case "redis":
+		return NewRedis(addr), nil
+	default:
+		return nil, fmt.Errorf("storage: unknown storage type %q", storageType)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces session keys so the server can share a Redis
+// instance with other applications.
+const redisKeyPrefix = "latency-server:session:"
+
+// redisStorage is a Storage backend for sharing sessions across multiple
+// server instances. Expiry is enforced natively by Redis TTLs in addition
+// to the Session.ExpiresAt field, so List never needs to sweep it.
+// Selected via --storage-type=redis.
+type redisStorage struct {
+	client *redis.Client
+}
+
+// NewRedis creates a Storage backed by the Redis instance at addr.
+func NewRedis(addr string) Storage {
+	return &redisStorage{client: redis.NewClient(&redis.Options{Addr: addr})}
+}
+
+func (r *redisStorage) key(id string) string {
+	return redisKeyPrefix + id
+}
+
+func (r *redisStorage) Save(ctx context.Context, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("storage: marshaling session %s: %w", s.ID, err)
+	}
+	var ttl time.Duration
+	if !s.ExpiresAt.IsZero() {
+		ttl = time.Until(s.ExpiresAt)
+		if ttl <= 0 {
+			ttl = time.Millisecond
+		}
+	}
+	if err := r.client.Set(ctx, r.key(s.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("storage: saving session %s: %w", s.ID, err)
+	}
+	return nil
+}
+
+func (r *redisStorage) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := r.client.Get(ctx, r.key(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: reading session %s: %w", id, err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("storage: decoding session %s: %w", id, err)
+	}
+	return &s, nil
+}
+
+func (r *redisStorage) List(ctx context.Context) ([]*Session, error) {
+	var out []*Session
+	iter := r.client.Scan(ctx, 0, redisKeyPrefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		data, err := r.client.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			continue
+		}
+		out = append(out, &s)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("storage: scanning sessions: %w", err)
+	}
+	return out, nil
+}
+
+func (r *redisStorage) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, r.key(id)).Err(); err != nil {
+		return fmt.Errorf("storage: deleting session %s: %w", id, err)
+	}
+	return nil
+}
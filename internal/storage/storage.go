@@ -0,0 +1,45 @@
+// Package storage persists client measurement sessions so a latency run
+// can be shared by URL and the UI can render historical comparisons across
+// reloads. Sessions are kept behind a Storage interface with mem, file,
+// and redis implementations, selected with --storage-type.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when no session exists for the given ID,
+// including when it has expired.
+var ErrNotFound = errors.New("storage: session not found")
+
+// Session is one client's recorded series of RTT samples (in
+// milliseconds), persisted under an opaque ID.
+type Session struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Samples   []int64   `json:"samples"`
+}
+
+// Expired reports whether s is past its ExpiresAt.
+func (s *Session) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && now.After(s.ExpiresAt)
+}
+
+// Storage persists and retrieves measurement sessions.
+type Storage interface {
+	// Save creates or overwrites the session.
+	Save(ctx context.Context, s *Session) error
+	// Get returns the session with id, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Session, error)
+	// List returns every session still known to the backend, in no
+	// particular order. Backends without native expiry (mem, file) include
+	// sessions past their ExpiresAt until SweepExpired reclaims them;
+	// backends with native TTL support (redis) never return them.
+	List(ctx context.Context) ([]*Session, error)
+	// Delete removes the session with id. It is not an error to delete an
+	// id that does not exist.
+	Delete(ctx context.Context, id string) error
+}
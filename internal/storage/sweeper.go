@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// SweepExpired runs every interval until stop is closed, deleting sessions
+// whose ExpiresAt has passed. Redis already enforces expiry natively, but
+// the mem and file backends rely on this sweeper to reclaim space for
+// sessions nobody ever fetches again.
+func SweepExpired(s Storage, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			sweepOnce(s)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func sweepOnce(s Storage) {
+	ctx := context.Background()
+	sessions, err := s.List(ctx)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, session := range sessions {
+		if session.Expired(now) {
+			s.Delete(ctx, session.ID)
+		}
+	}
+}
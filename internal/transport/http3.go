@@ -0,0 +1,25 @@
+package transport
+
+import (
+	"crypto/tls"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/webtransport-go"
+)
+
+// NewHTTP3Server builds the *webtransport.Server that serves cfg.Handler
+// over HTTP/3 (QUIC) on cfg.Addr. Callers that also need to upgrade
+// WebTransport sessions, rather than plain HTTP/3 requests, must pass
+// this same instance to ListenAndServe and reuse it for Upgrade: a second,
+// independently constructed server has no knowledge of sessions accepted
+// by the first, so upgrades against it fail even though the listener looks
+// identical.
+func NewHTTP3Server(cfg Config) *webtransport.Server {
+	return &webtransport.Server{
+		H3: &http3.Server{
+			Addr:      cfg.Addr,
+			Handler:   cfg.Handler,
+			TLSConfig: &tls.Config{},
+		},
+	}
+}
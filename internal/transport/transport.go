@@ -0,0 +1,76 @@
+// Package transport builds the listener(s) latency-server serves on.
+// Measured latency differs substantially between plain TCP+TLS 1.3,
+// HTTP/2's multiplexed streams, and QUIC's 0-RTT handshake, so the server
+// can expose all three from one binary and let the UI compare them.
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/quic-go/webtransport-go"
+	"golang.org/x/net/http2"
+)
+
+// Config describes how to build and serve the configured listener(s).
+type Config struct {
+	Addr     string
+	Handler  http.Handler
+	CertFile string
+	KeyFile  string
+	// HTTP3 additionally starts a QUIC listener on Addr, serving Handler
+	// over HTTP/3. It requires CertFile/KeyFile, since HTTP/3 has no
+	// cleartext mode.
+	HTTP3 bool
+}
+
+// NewServer builds the *http.Server for cfg, enabling HTTP/2 when TLS is
+// configured. Plain HTTP/1.1 is served when CertFile/KeyFile are both
+// empty.
+func NewServer(cfg Config) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: cfg.Handler,
+	}
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.HTTP3 {
+			return nil, fmt.Errorf("transport: --http3 requires --tls-cert and --tls-key")
+		}
+		return srv, nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("transport: both --tls-cert and --tls-key must be set to enable TLS")
+	}
+	srv.TLSConfig = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
+		return nil, fmt.Errorf("transport: configuring HTTP/2: %w", err)
+	}
+	return srv, nil
+}
+
+// ListenAndServe starts srv (TLS when cfg.CertFile is set, plaintext
+// otherwise) and, if cfg.HTTP3 is set, http3Srv's HTTP/3 listener on the
+// same Addr, returning as soon as either stops. http3Srv must be the same
+// instance returned by NewHTTP3Server(cfg) that the caller also uses to
+// upgrade WebTransport sessions, and is required whenever cfg.HTTP3 is
+// set.
+func ListenAndServe(srv *http.Server, cfg Config, http3Srv *webtransport.Server) error {
+	errCh := make(chan error, 2)
+
+	go func() {
+		if cfg.CertFile != "" {
+			errCh <- srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	if cfg.HTTP3 {
+		go func() {
+			errCh <- http3Srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+		}()
+	}
+
+	return <-errCh
+}
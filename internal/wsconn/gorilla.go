@@ -0,0 +1,43 @@
+package wsconn
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// gorillaConn adapts a *websocket.Conn to Conn.
+type gorillaConn struct {
+	conn *websocket.Conn
+}
+
+// NewGorilla wraps an already-upgraded gorilla WebSocket connection.
+func NewGorilla(conn *websocket.Conn) Conn {
+	return &gorillaConn{conn: conn}
+}
+
+func (g *gorillaConn) ReadMessage() ([]byte, error) {
+	_, data, err := g.conn.ReadMessage()
+	return data, err
+}
+
+func (g *gorillaConn) WriteMessage(data []byte) error {
+	return g.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (g *gorillaConn) Ping(deadline time.Time) error {
+	return g.conn.WriteControl(websocket.PingMessage, nil, deadline)
+}
+
+func (g *gorillaConn) SetPongHandler(fn func()) {
+	g.conn.SetPongHandler(func(string) error {
+		fn()
+		return nil
+	})
+}
+
+func (g *gorillaConn) SupportsPing() bool { return true }
+
+func (g *gorillaConn) Close() error {
+	return g.conn.Close()
+}
@@ -0,0 +1,55 @@
+package wsconn
+
+import (
+	"time"
+
+	"github.com/quic-go/webtransport-go"
+)
+
+// webtransportConn adapts a WebTransport session's single bidirectional
+// stream to Conn. WebTransport has no ping/pong control frames of its
+// own, so Ping and SetPongHandler are no-ops and the application-level
+// keep-alive used to count lost pings on plain WebSockets does not run for
+// these connections.
+type webtransportConn struct {
+	session *webtransport.Session
+	stream  *webtransport.Stream
+}
+
+// NewWebTransport accepts the session's first bidirectional stream,
+// mirroring how a gorilla WebSocket connection is ready to read/write
+// immediately after Upgrade.
+func NewWebTransport(session *webtransport.Session) (Conn, error) {
+	stream, err := session.AcceptStream(session.Context())
+	if err != nil {
+		return nil, err
+	}
+	return &webtransportConn{session: session, stream: stream}, nil
+}
+
+func (w *webtransportConn) ReadMessage() ([]byte, error) {
+	buf := make([]byte, 64*1024)
+	n, err := w.stream.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func (w *webtransportConn) WriteMessage(data []byte) error {
+	_, err := w.stream.Write(data)
+	return err
+}
+
+func (w *webtransportConn) Ping(time.Time) error   { return nil }
+func (w *webtransportConn) SetPongHandler(func()) {}
+
+// SupportsPing is false: WebTransport has no ping/pong control frames, so
+// callers must not treat the no-op Ping above as a real liveness probe
+// (every call would otherwise look like a missed pong).
+func (w *webtransportConn) SupportsPing() bool { return false }
+
+func (w *webtransportConn) Close() error {
+	w.stream.Close()
+	return w.session.CloseWithError(0, "")
+}
@@ -0,0 +1,31 @@
+// Package wsconn abstracts the bidirectional message connection used by
+// the latency WebSocket handler, so it can be implemented once and driven
+// by either an RFC 6455 WebSocket (gorilla/websocket) or a WebTransport
+// session negotiated over HTTP/3, without forking the handler into two
+// copies.
+package wsconn
+
+import "time"
+
+// Conn is the minimal surface the latency handler needs, regardless of
+// which transport carried the connection.
+type Conn interface {
+	// ReadMessage blocks for the next message.
+	ReadMessage() ([]byte, error)
+	// WriteMessage sends data as one message.
+	WriteMessage(data []byte) error
+	// Ping sends a keep-alive probe, used to measure packet loss on
+	// otherwise idle connections. Transports with no native ping/pong
+	// (WebTransport) implement it as a no-op.
+	Ping(deadline time.Time) error
+	// SetPongHandler registers fn to be called when a pong (or
+	// transport-equivalent liveness signal) is received.
+	SetPongHandler(fn func())
+	// SupportsPing reports whether Ping/SetPongHandler actually measure
+	// liveness on this connection. Callers that track packet loss from
+	// missed pings must skip that tracking entirely when this is false,
+	// rather than calling the no-op Ping and recording every tick as a
+	// miss.
+	SupportsPing() bool
+	Close() error
+}